@@ -0,0 +1,284 @@
+package docxplate
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filter - a single named transform in a placeholder's pipe chain, e.g.
+// "truncate 20" in "{{User.Name | truncate 20}}"
+type Filter struct {
+	Name string
+	Args []string
+}
+
+// FilterFunc - implementation of a named filter, run left-to-right over a
+// Param's Value before it's substituted into the document
+type FilterFunc func(in string, args ...string) (string, error)
+
+// builtinFilters - filters available without registration
+var builtinFilters = map[string]FilterFunc{
+	"upper":     filterUpper,
+	"lower":     filterLower,
+	"title":     filterTitle,
+	"trim":      filterTrim,
+	"truncate":  filterTruncate,
+	"default":   filterDefault,
+	"date":      filterDate,
+	"number":    filterNumber,
+	"escapeXML": filterEscapeXML,
+	"md5":       filterMD5,
+	"sha256":    filterSHA256,
+}
+
+func filterUpper(in string, _ ...string) (string, error) { return strings.ToUpper(in), nil }
+func filterLower(in string, _ ...string) (string, error) { return strings.ToLower(in), nil }
+func filterTitle(in string, _ ...string) (string, error) { return strings.Title(in), nil }
+func filterTrim(in string, _ ...string) (string, error)  { return strings.TrimSpace(in), nil }
+
+func filterTruncate(in string, args ...string) (string, error) {
+	if len(args) == 0 {
+		return in, fmt.Errorf("truncate: missing length argument")
+	}
+
+	n, err := strconv.Atoi(args[0])
+	if err != nil {
+		return in, fmt.Errorf("truncate: invalid length %q", args[0])
+	}
+
+	runes := []rune(in)
+	if len(runes) <= n {
+		return in, nil
+	}
+	return string(runes[:n]), nil
+}
+
+// filterDefault - fall back to args[0] when in is empty
+func filterDefault(in string, args ...string) (string, error) {
+	if in != "" || len(args) == 0 {
+		return in, nil
+	}
+	return args[0], nil
+}
+
+// filterDate - reparse+reformat in via time layouts: `date 2006-01-02 "Jan _2, 2006"`
+// (a layout containing spaces must be quoted to stay a single argument)
+func filterDate(in string, args ...string) (string, error) {
+	if len(args) < 2 {
+		return in, fmt.Errorf("date: requires <inLayout> <outLayout> arguments")
+	}
+
+	t, err := time.Parse(args[0], in)
+	if err != nil {
+		return in, err
+	}
+	return t.Format(args[1]), nil
+}
+
+// filterNumber - thousands separator, optional decimal count: "number 2"
+func filterNumber(in string, args ...string) (string, error) {
+	f, err := strconv.ParseFloat(in, 64)
+	if err != nil {
+		return in, err
+	}
+
+	decimals := 0
+	if len(args) > 0 {
+		if decimals, err = strconv.Atoi(args[0]); err != nil {
+			return in, fmt.Errorf("number: invalid decimals %q", args[0])
+		}
+	}
+
+	return groupThousands(strconv.FormatFloat(f, 'f', decimals, 64)), nil
+}
+
+// groupThousands - insert "," every 3 digits of the integer part of s
+func groupThousands(s string) string {
+	neg := strings.HasPrefix(s, "-")
+	if neg {
+		s = s[1:]
+	}
+
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i:]
+	}
+
+	var grouped strings.Builder
+	for i, d := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(d)
+	}
+
+	out := grouped.String() + fracPart
+	if neg {
+		out = "-" + out
+	}
+	return out
+}
+
+func filterEscapeXML(in string, _ ...string) (string, error) {
+	r := strings.NewReplacer(
+		`&`, "&amp;",
+		`<`, "&lt;",
+		`>`, "&gt;",
+		`"`, "&quot;",
+		`'`, "&apos;",
+	)
+	return r.Replace(in), nil
+}
+
+func filterMD5(in string, _ ...string) (string, error) {
+	sum := md5.Sum([]byte(in))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func filterSHA256(in string, _ ...string) (string, error) {
+	sum := sha256.Sum256([]byte(in))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// RegisterFilter - add or override a named filter, so custom transforms can
+// be used in placeholder pipe chains without forking the built-in registry.
+func (t *Template) RegisterFilter(name string, fn FilterFunc) {
+	if t.filters == nil {
+		t.filters = map[string]FilterFunc{}
+	}
+	t.filters[name] = fn
+}
+
+// filterFunc - look up a filter by name; custom registrations take priority
+// over the built-ins
+func (t *Template) filterFunc(name string) (FilterFunc, bool) {
+	if fn, ok := t.filters[name]; ok {
+		return fn, true
+	}
+	fn, ok := builtinFilters[name]
+	return fn, ok
+}
+
+// ApplyFilters - run p's filter chain over p.Value, left-to-right, using t's
+// registered filters. Runs before trigger/selector evaluation, so e.g.
+// TriggerOnEmpty sees the post-filter value.
+func (p *Param) ApplyFilters(t *Template) error {
+	for _, f := range p.Filters {
+		fn, ok := t.filterFunc(f.Name)
+		if !ok {
+			return fmt.Errorf("docxplate: unknown filter %q on %s", f.Name, p.AbsoluteKey)
+		}
+
+		v, err := fn(p.Value, f.Args...)
+		if err != nil {
+			return fmt.Errorf("docxplate: filter %q on %s: %w", f.Name, p.AbsoluteKey, err)
+		}
+		p.Value = v
+	}
+
+	return nil
+}
+
+// PrepareValue - run the substitution-time pipeline over p.Value: filters
+// first, then the plural/gender selector, both against t's registries/rules.
+// Called by the render loop for every Param right before its placeholder is
+// substituted, so triggers downstream (RunTrigger) always see the final value.
+func (p *Param) PrepareValue(t *Template) error {
+	if err := p.ApplyFilters(t); err != nil {
+		return err
+	}
+
+	p.ResolveSelector(t)
+
+	return nil
+}
+
+// parseFilterChain - split the raw trailing placeholder contents into an
+// ordered filter chain plus whatever's left over (selector/trigger syntax).
+// Returns a nil chain when raw has no leading "|".
+func parseFilterChain(raw []byte) ([]Filter, []byte) {
+	s := strings.TrimSpace(string(raw))
+	if !strings.HasPrefix(s, "|") {
+		return nil, raw
+	}
+
+	segments, rest := splitPipeSegments(s)
+
+	filters := make([]Filter, 0, len(segments))
+	for _, seg := range segments {
+		tokens := tokenizeFilterArgs(seg)
+		if len(tokens) == 0 {
+			continue
+		}
+		filters = append(filters, Filter{Name: tokens[0], Args: tokens[1:]})
+	}
+
+	return filters, []byte(rest)
+}
+
+// splitPipeSegments - split "| a | b c \"d\" :trigger" into
+// ["", " a ", ` b c "d"`] (an empty segment before the leading "|", spaces
+// left for tokenizeFilterArgs to drop), plus the leftover selector/trigger
+// suffix (starting at its leading ":", with the separating space trimmed
+// off). Respects quoted string args: a "|" inside quotes doesn't start a new
+// segment.
+func splitPipeSegments(s string) ([]string, string) {
+	var segments []string
+	var cur strings.Builder
+	inQuotes := false
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case c == '"':
+			inQuotes = !inQuotes
+			cur.WriteRune(c)
+		case c == '|' && !inQuotes:
+			segments = append(segments, cur.String())
+			cur.Reset()
+		case c == ':' && !inQuotes && i > 0 && runes[i-1] == ' ':
+			segments = append(segments, strings.TrimSuffix(cur.String(), " "))
+			return segments, string(runes[i:])
+		default:
+			cur.WriteRune(c)
+		}
+	}
+
+	segments = append(segments, cur.String())
+	return segments, ""
+}
+
+// tokenizeFilterArgs - split a filter segment into words, treating a
+// "quoted string" as a single token with the surrounding quotes stripped
+func tokenizeFilterArgs(seg string) []string {
+	var tokens []string
+	var cur strings.Builder
+	inQuotes := false
+
+	flush := func() {
+		if cur.Len() > 0 {
+			tokens = append(tokens, cur.String())
+			cur.Reset()
+		}
+	}
+
+	for _, c := range seg {
+		switch {
+		case c == '"':
+			inQuotes = !inQuotes
+		case c == ' ' && !inQuotes:
+			flush()
+		default:
+			cur.WriteRune(c)
+		}
+	}
+	flush()
+
+	return tokens
+}
@@ -0,0 +1,56 @@
+package docxplate
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestParseFilterChain(t *testing.T) {
+	filters, rest := parseFilterChain([]byte(`| upper | truncate 20 | default "n/a" :empty:remove:row`))
+
+	want := []Filter{
+		{Name: "upper"},
+		{Name: "truncate", Args: []string{"20"}},
+		{Name: "default", Args: []string{"n/a"}},
+	}
+	if len(filters) != len(want) {
+		t.Fatalf("got %d filters, want %d: %+v", len(filters), len(want), filters)
+	}
+	for i, f := range filters {
+		if f.Name != want[i].Name || len(f.Args) != len(want[i].Args) {
+			t.Fatalf("filter %d = %+v, want %+v", i, f, want[i])
+		}
+		for j, a := range f.Args {
+			if a != want[i].Args[j] {
+				t.Fatalf("filter %d arg %d = %q, want %q", i, j, a, want[i].Args[j])
+			}
+		}
+	}
+
+	if string(rest) != ":empty:remove:row" {
+		t.Fatalf("rest = %q", rest)
+	}
+}
+
+func TestParseFilterChain_NoFilters(t *testing.T) {
+	filters, rest := parseFilterChain([]byte(` :empty:remove:row`))
+	if filters != nil {
+		t.Fatalf("expected no filters, got %+v", filters)
+	}
+	if string(rest) != ` :empty:remove:row` {
+		t.Fatalf("rest = %q", rest)
+	}
+}
+
+func TestFilterTruncate_MultiByteRunes(t *testing.T) {
+	out, err := filterTruncate("Café Müller", "4")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "Café" {
+		t.Fatalf("got %q, want %q", out, "Café")
+	}
+	if !utf8.ValidString(out) {
+		t.Fatalf("truncated output is not valid UTF-8: %q", out)
+	}
+}
@@ -0,0 +1,159 @@
+package docxplate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ParamKind - inferred shape of a placeholder discovered by ExtractParams
+type ParamKind string
+
+// Param kinds
+const (
+	ParamKindScalar      ParamKind = "scalar"
+	ParamKindSlice       ParamKind = "slice"
+	ParamKindStructField ParamKind = "struct-field"
+	ParamKindListParent  ParamKind = "list-parent"
+)
+
+// ParamSpec - one placeholder found while walking the document: its key
+// forms, inferred kind, trigger/filter chain (if any) and surrounding scope.
+// Generated by Template.ExtractParams, used to drive schema/form generation
+// or to check ParamList coverage with ValidateParams before rendering.
+type ParamSpec struct {
+	AbsoluteKey string
+	CompactKey  string
+	Kind        ParamKind
+	Trigger     *ParamTrigger
+	Filters     []Filter
+	Scope       string // paragraph/cell/row/list/table/section
+}
+
+// MissingParam - a ParamSpec found in the template but not covered by the
+// ParamList passed to ValidateParams
+type MissingParam struct {
+	ParamSpec
+	Reason string
+}
+
+// ExtractParams - walk the docx once and return every unique placeholder
+// found. Lets callers generate a JSON/YAML schema of required inputs, drive
+// form generation, or check ParamList coverage with ValidateParams.
+func (t *Template) ExtractParams() ([]ParamSpec, error) {
+	if t.doc == nil {
+		return nil, fmt.Errorf("docxplate: no document loaded")
+	}
+
+	seen := map[string]bool{}
+	var specs []ParamSpec
+
+	re := regexp.MustCompile(ParamPattern)
+
+	t.doc.Walk(func(xnode *xmlNode) {
+		for _, raw := range re.FindAll([]byte(xnode.Content), -1) {
+			p := NewParamFromRaw(raw)
+			if p == nil || seen[p.AbsoluteKey] {
+				continue
+			}
+			seen[p.AbsoluteKey] = true
+
+			specs = append(specs, ParamSpec{
+				AbsoluteKey: p.AbsoluteKey,
+				CompactKey:  p.CompactKey,
+				Kind:        paramKindOf(p.AbsoluteKey, xnode),
+				Trigger:     p.Trigger,
+				Filters:     p.Filters,
+				Scope:       scopeOf(xnode),
+			})
+		}
+	})
+
+	return specs, nil
+}
+
+// paramKindOf - best-effort classification of a placeholder's shape from its
+// key and the node it lives on: a placeholder in a list item is a
+// list-parent, one inside a table is a slice, and anything else is a struct
+// field when its key is dotted or a scalar otherwise. Whether the placeholder
+// is a "{{#Key}}" block marker is not examined.
+func paramKindOf(key string, xnode *xmlNode) ParamKind {
+	if isListItem, _ := xnode.IsListItem(); isListItem {
+		return ParamKindListParent
+	}
+	if xnode.closestUp([]string{"w-tbl"}) != nil {
+		return ParamKindSlice
+	}
+	if strings.Contains(key, ".") {
+		return ParamKindStructField
+	}
+	return ParamKindScalar
+}
+
+// scopeOf - name of the first of cell/row/list/table/section (checked in
+// that order) enclosing xnode, or "paragraph" when none does, mirroring the
+// scopes RunTrigger understands
+func scopeOf(xnode *xmlNode) string {
+	switch {
+	case xnode.closestUp(NodeCellTypes) != nil:
+		return "cell"
+	case xnode.closestUp(NodeRowTypes) != nil:
+		return "row"
+	}
+
+	if isListItem, _ := xnode.IsListItem(); isListItem {
+		return "list"
+	}
+	if xnode.closestUp([]string{"w-tbl"}) != nil {
+		return "table"
+	}
+	if xnode.closestUp(NodeSectionTypes) != nil {
+		return "section"
+	}
+
+	return "paragraph"
+}
+
+// ValidateParams - report placeholders present in the template but missing
+// from data, with scope info so callers can decide whether a missing slice
+// is fatal or should just fall through to a :remove trigger
+func (t *Template) ValidateParams(data ParamList) []MissingParam {
+	specs, err := t.ExtractParams()
+	if err != nil {
+		return nil
+	}
+
+	have := collectCompactKeys(data)
+
+	var missing []MissingParam
+	for _, spec := range specs {
+		if have[spec.CompactKey] {
+			continue
+		}
+		missing = append(missing, MissingParam{
+			ParamSpec: spec,
+			Reason:    "no matching key in supplied data",
+		})
+	}
+
+	return missing
+}
+
+// collectCompactKeys - CompactKey of every Param in data, at every depth
+// (mirrors Param.Walk), so a nested/slice field like "Users.Name" counts as
+// supplied even though it only appears under a top-level Param's Params.
+func collectCompactKeys(data ParamList) map[string]bool {
+	keys := map[string]bool{}
+
+	for _, p := range data {
+		if p == nil {
+			continue
+		}
+		keys[p.CompactKey] = true
+		p.Walk(func(p2 *Param) {
+			keys[p2.CompactKey] = true
+		})
+	}
+
+	return keys
+}
@@ -0,0 +1,18 @@
+package docxplate
+
+import "testing"
+
+func TestCollectCompactKeys_Nested(t *testing.T) {
+	company := NewParam("Company")
+	company.Params = ParamList{NewParam("Name"), NewParam("Email")}
+
+	data := ParamList{company}
+
+	keys := collectCompactKeys(data)
+
+	for _, want := range []string{"Company", "Company.Name", "Company.Email"} {
+		if !keys[want] {
+			t.Errorf("expected %q in collected keys, got %+v", want, keys)
+		}
+	}
+}
@@ -9,7 +9,7 @@ import (
 
 // ParamPattern - regex pattern to identify params
 // const ParamPattern = `{{(#|)[\w\.]+?(| .| )+?}}`
-const ParamPattern = `{{(#|)([\w\.]+?)(| .| +)(|(:[a-z]+)+)+?}}`
+const ParamPattern = `{{(#|)([\w\.]+)(| .| +)([^{}]*)}}`
 
 // Param ..
 type Param struct {
@@ -25,6 +25,14 @@ type Param struct {
 	CompactKey  string // Users.Name
 
 	Trigger *ParamTrigger
+
+	// Selector - parsed ":plural:.." / ":gender:.." arms, resolved against
+	// Value (via the owning Template's locale rules) at render time
+	Selector *ParamSelector
+
+	// Filters - ordered "| name arg1 arg2" pipe chain, applied to Value
+	// before Selector/Trigger ever see it
+	Filters []Filter
 }
 
 // NewParam ..
@@ -47,7 +55,34 @@ func NewParamFromRaw(raw []byte) *Param {
 	}
 
 	p := NewParam(string(matches[0][2]))
-	p.Trigger = NewParamTrigger(matches[0][4])
+
+	// Pipe filter chain comes first, e.g. "{{Name | upper | default \"n/a\"}}",
+	// leaving only the selector/trigger syntax (if any) behind it.
+	rest := matches[0][4]
+	p.Filters, rest = parseFilterChain(rest)
+
+	// A :plural/:gender selector may be mixed in with the trigger part of
+	// the placeholder, e.g. "{{Count :plural:one=item:other=items}}".
+	// Pull it out first so NewParamTrigger only ever sees trigger syntax.
+	if sel, stripped := NewParamSelectorFromRaw(rest); sel != nil {
+		p.Selector = sel
+		rest = stripped
+	}
+
+	// An "if="/"unless=" clause replaces the bare :empty sentinel with a
+	// full boolean expression; pull it out before NewParamTrigger sees the
+	// remaining scope/command tokens.
+	expr, negate, rest := extractIfUnless(rest)
+
+	p.Trigger = NewParamTrigger(rest)
+	if expr != nil {
+		if negate {
+			p.Trigger.Unless = expr
+		} else {
+			p.Trigger.If = expr
+		}
+	}
+
 	return p
 }
 
@@ -172,7 +207,7 @@ func (p *Param) extractTriggerFrom(buf []byte) *ParamTrigger {
 // RunTrigger - execute trigger
 func (p *Param) RunTrigger(xnode *xmlNode) {
 
-	if p.Trigger.On == TriggerOnEmpty && p.Value != "" {
+	if !p.triggerConditionMet() {
 		return
 	}
 
@@ -236,6 +271,49 @@ func (p *Param) RunTrigger(xnode *xmlNode) {
 
 }
 
+// triggerConditionMet - whether RunTrigger should act: evaluates an
+// "if="/"unless=" expression when the trigger carries one, otherwise falls
+// back to the plain :empty sentinel
+func (p *Param) triggerConditionMet() bool {
+	switch {
+	case p.Trigger.If != nil:
+		ok, err := p.Trigger.If.Eval(p)
+		return err == nil && ok
+	case p.Trigger.Unless != nil:
+		ok, err := p.Trigger.Unless.Eval(p)
+		return err == nil && !ok
+	default:
+		return p.Trigger.On != TriggerOnEmpty || p.Value == ""
+	}
+}
+
+// root - walk up to the top-level Param owning this one's tree
+func (p *Param) root() *Param {
+	n := p
+	for n.parent != nil {
+		n = n.parent
+	}
+	return n
+}
+
+// resolveRef - find another Param in the same tree by absolute or compact
+// key, for trigger expressions that reference a key other than their own
+func (p *Param) resolveRef(key string) (*Param, bool) {
+	root := p.root()
+	if root.AbsoluteKey == key || root.CompactKey == key {
+		return root, true
+	}
+
+	var found *Param
+	root.Walk(func(p2 *Param) {
+		if found == nil && (p2.AbsoluteKey == key || p2.CompactKey == key) {
+			found = p2
+		}
+	})
+
+	return found, found != nil
+}
+
 // String - compact debug information as string
 func (p *Param) String() string {
 	s := fmt.Sprintf("%34s=%-20s", p.AbsoluteKey, p.Value)
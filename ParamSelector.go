@@ -0,0 +1,156 @@
+package docxplate
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SelectorKind - which CLDR resolver a ParamSelector is evaluated with
+type SelectorKind string
+
+// Selector kinds
+const (
+	SelectorKindPlural SelectorKind = "plural"
+	SelectorKindGender SelectorKind = "gender"
+)
+
+// ParamSelector - plural/gender arms attached to a Param, e.g.
+// :plural:one=item:other=items -> Kind=plural, Arms={"one": "item", "other": "items"}
+type ParamSelector struct {
+	Kind SelectorKind
+	Arms map[string]string
+}
+
+// NewParamSelectorFromRaw - parse a leading ":plural:.."/":gender:.." selector
+// out of raw trigger contents, e.g.
+// "{{Count :plural:one=item:other=items}}"
+// "{{Person.Gender :gender:male=he:female=she:other=they}}"
+// Returns nil plus the untouched raw if it contains no selector.
+//
+// Arms are scanned by hand rather than matched with one regex: each arm stops
+// at the first ':'/'}'/space, and the scan itself stops - without
+// consuming anything - the moment it sees an "if="/"unless=" clause, so a
+// selector immediately followed by a conditional trigger (e.g.
+// "{{Count :plural:one=item:other=items :if=Count>0:remove:row}}") never has
+// its trigger clause swallowed as a bogus arm.
+func NewParamSelectorFromRaw(raw []byte) (*ParamSelector, []byte) {
+	s := string(raw)
+
+	start, kind := findSelectorStart(s)
+	if start < 0 {
+		return nil, raw
+	}
+
+	pos := start + 1 + len(kind) // just past ":plural" / ":gender"
+	arms := map[string]string{}
+
+	for pos < len(s) && s[pos] == ':' {
+		afterColon := s[pos+1:]
+		if strings.HasPrefix(afterColon, "if=") || strings.HasPrefix(afterColon, "unless=") {
+			break
+		}
+
+		eq := strings.IndexByte(afterColon, '=')
+		if eq < 0 {
+			break
+		}
+
+		key := afterColon[:eq]
+		if !isWordToken(key) {
+			break
+		}
+
+		valRest := afterColon[eq+1:]
+		vEnd := 0
+		for vEnd < len(valRest) && valRest[vEnd] != ':' && valRest[vEnd] != '}' && valRest[vEnd] != ' ' {
+			vEnd++
+		}
+
+		arms[key] = valRest[:vEnd]
+		pos += 1 + len(key) + 1 + vEnd // ':' + key + '=' + value
+	}
+
+	if len(arms) == 0 {
+		return nil, raw
+	}
+
+	ps := &ParamSelector{Kind: SelectorKind(kind), Arms: arms}
+	rest := s[:start] + s[pos:]
+	return ps, []byte(rest)
+}
+
+// findSelectorStart - index of a ":plural"/":gender" keyword in s and which
+// one it is, or (-1, "") if neither appears as a clean ":word" token.
+func findSelectorStart(s string) (int, string) {
+	start, kind := -1, ""
+
+	for _, k := range []string{"plural", "gender"} {
+		i := strings.Index(s, ":"+k)
+		if i < 0 {
+			continue
+		}
+
+		after := i + 1 + len(k)
+		if after < len(s) && s[after] != ':' {
+			continue // e.g. ":pluralish" isn't the keyword
+		}
+
+		if start == -1 || i < start {
+			start, kind = i, k
+		}
+	}
+
+	return start, kind
+}
+
+// isWordToken - non-empty and made up of letters/digits/underscore, the same
+// shape a trigger/selector key is expected to have
+func isWordToken(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Resolve - literal text for the given CLDR category, falling back to the
+// "other" arm when the category has no explicit arm (as CLDR requires).
+func (ps *ParamSelector) Resolve(category string) (string, bool) {
+	if ps == nil {
+		return "", false
+	}
+
+	if s, ok := ps.Arms[category]; ok {
+		return s, true
+	}
+
+	s, ok := ps.Arms["other"]
+	return s, ok
+}
+
+// ResolveSelector - replace p.Value with the selector arm matching p.Value,
+// resolved through the Template's plural/gender rules. No-op when p has no
+// selector attached.
+func (p *Param) ResolveSelector(t *Template) {
+	if p.Selector == nil {
+		return
+	}
+
+	var category string
+	switch p.Selector.Kind {
+	case SelectorKindPlural:
+		category = t.pluralCategory(p.Value)
+	case SelectorKindGender:
+		category = t.genderCategory(p.Value)
+	}
+
+	if s, ok := p.Selector.Resolve(category); ok {
+		p.Value = s
+	}
+}
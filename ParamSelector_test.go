@@ -0,0 +1,74 @@
+package docxplate
+
+import "testing"
+
+func TestNewParamSelectorFromRaw(t *testing.T) {
+	sel, rest := NewParamSelectorFromRaw([]byte(`:plural:one=item:other=items`))
+	if sel == nil || sel.Kind != SelectorKindPlural {
+		t.Fatalf("expected a plural selector, got %+v", sel)
+	}
+	if sel.Arms["one"] != "item" || sel.Arms["other"] != "items" {
+		t.Fatalf("unexpected arms: %+v", sel.Arms)
+	}
+	if string(rest) != "" {
+		t.Fatalf("expected nothing left over, got %q", rest)
+	}
+}
+
+func TestNewParamSelectorFromRaw_Unicode(t *testing.T) {
+	sel, _ := NewParamSelectorFromRaw([]byte(`:plural:one=Stück:other=Stücke`))
+	if sel == nil {
+		t.Fatal("expected a selector, got nil")
+	}
+	if sel.Arms["one"] != "Stück" {
+		t.Fatalf("one arm truncated: got %q", sel.Arms["one"])
+	}
+	if sel.Arms["other"] != "Stücke" {
+		t.Fatalf("other arm missing/truncated: got %q (arms=%+v)", sel.Arms["other"], sel.Arms)
+	}
+}
+
+// A selector immediately followed by an "if="/"unless=" trigger clause must
+// leave that clause intact for extractIfUnless, instead of swallowing it as
+// a bogus arm.
+func TestNewParamSelectorFromRaw_StopsBeforeIfClause(t *testing.T) {
+	sel, rest := NewParamSelectorFromRaw([]byte(`:plural:one=item:other=items :if=Count>0:remove:row`))
+	if sel == nil {
+		t.Fatal("expected a selector, got nil")
+	}
+	if sel.Arms["one"] != "item" || sel.Arms["other"] != "items" {
+		t.Fatalf("unexpected arms: %+v", sel.Arms)
+	}
+	if _, ok := sel.Arms["if"]; ok {
+		t.Fatalf("if= clause leaked into arms: %+v", sel.Arms)
+	}
+	if string(rest) != " :if=Count>0:remove:row" {
+		t.Fatalf("rest = %q, want the if= clause intact", rest)
+	}
+}
+
+// A trailing scope/command clause with no if=/unless= must not leave a
+// trailing space baked into the last arm's value.
+func TestNewParamSelectorFromRaw_TrimsTrailingSpace(t *testing.T) {
+	sel, rest := NewParamSelectorFromRaw([]byte(`:plural:one=item:other=items :remove:row`))
+	if sel == nil {
+		t.Fatal("expected a selector, got nil")
+	}
+	if sel.Arms["other"] != "items" {
+		t.Fatalf("other arm = %q, want no trailing space", sel.Arms["other"])
+	}
+	if string(rest) != " :remove:row" {
+		t.Fatalf("rest = %q", rest)
+	}
+}
+
+func TestParamSelector_Resolve(t *testing.T) {
+	sel := &ParamSelector{Kind: SelectorKindGender, Arms: map[string]string{"male": "he", "other": "they"}}
+
+	if s, ok := sel.Resolve("male"); !ok || s != "he" {
+		t.Fatalf("expected he, got %q (ok=%v)", s, ok)
+	}
+	if s, ok := sel.Resolve("female"); !ok || s != "they" {
+		t.Fatalf("expected fallback to other, got %q (ok=%v)", s, ok)
+	}
+}
@@ -0,0 +1,173 @@
+package docxplate
+
+import "strconv"
+
+// PluralResolver - computes the CLDR plural category ("zero", "one", "two",
+// "few", "many", "other") for a numeric value given as a string.
+type PluralResolver func(n string) string
+
+// GenderResolver - computes the gender category ("male", "female", "other")
+// for a value, e.g. a Value such as "male"/"female" coming straight from
+// the data, or a custom mapping registered by the caller.
+type GenderResolver func(value string) string
+
+// defaultPluralRules - CLDR plural rules for a handful of common locales.
+// Not exhaustive: callers needing another locale should RegisterPluralRule.
+var defaultPluralRules = map[string]PluralResolver{
+	"en": pluralRuleEN,
+	"de": pluralRuleEN, // same one/other split as English
+	"fr": pluralRuleFR,
+	"ru": pluralRuleRU,
+	"pl": pluralRulePL,
+	"ar": pluralRuleAR,
+}
+
+// pluralRuleEN - CLDR English: "one" is exactly 1, everything else "other"
+func pluralRuleEN(n string) string {
+	if isOne(n) {
+		return "one"
+	}
+	return "other"
+}
+
+// pluralRuleFR - CLDR French: 0 and 1 are "one"
+func pluralRuleFR(n string) string {
+	if f, ok := asFloat(n); ok && f >= 0 && f < 2 {
+		return "one"
+	}
+	return "other"
+}
+
+// pluralRuleRU - CLDR Russian: simplified integer rule (v=0 cases only)
+func pluralRuleRU(n string) string {
+	i, ok := asInt(n)
+	if !ok {
+		return "other"
+	}
+
+	mod10, mod100 := i%10, i%100
+	switch {
+	case mod10 == 1 && mod100 != 11:
+		return "one"
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return "few"
+	default:
+		return "many"
+	}
+}
+
+// pluralRulePL - CLDR Polish: simplified integer rule (v=0 cases only)
+func pluralRulePL(n string) string {
+	i, ok := asInt(n)
+	if !ok {
+		return "other"
+	}
+
+	if i == 1 {
+		return "one"
+	}
+
+	mod10, mod100 := i%10, i%100
+	if mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14) {
+		return "few"
+	}
+	return "many"
+}
+
+// pluralRuleAR - CLDR Arabic: simplified integer rule
+func pluralRuleAR(n string) string {
+	i, ok := asInt(n)
+	if !ok {
+		return "other"
+	}
+
+	switch {
+	case i == 0:
+		return "zero"
+	case i == 1:
+		return "one"
+	case i == 2:
+		return "two"
+	case i%100 >= 3 && i%100 <= 10:
+		return "few"
+	case i%100 >= 11:
+		return "many"
+	default:
+		return "other"
+	}
+}
+
+// defaultGenderRule - "male"/"female" pass through, anything else is "other"
+func defaultGenderRule(value string) string {
+	switch value {
+	case "male", "female":
+		return value
+	default:
+		return "other"
+	}
+}
+
+func isOne(n string) bool {
+	f, ok := asFloat(n)
+	return ok && f == 1
+}
+
+func asInt(n string) (int, bool) {
+	i, err := strconv.Atoi(n)
+	return i, err == nil
+}
+
+func asFloat(n string) (float64, bool) {
+	f, err := strconv.ParseFloat(n, 64)
+	return f, err == nil
+}
+
+// SetLocale - switch the CLDR plural rule set used to resolve ":plural"
+// selectors. Defaults to "en" when never called. Unknown tags are ignored,
+// leaving the previous rule set (or the "en" default) in place.
+func (t *Template) SetLocale(tag string) {
+	t.locale = tag
+
+	if fn, ok := t.pluralRules[tag]; ok {
+		t.pluralRule = fn
+		return
+	}
+
+	if fn, ok := defaultPluralRules[tag]; ok {
+		t.pluralRule = fn
+	}
+}
+
+// RegisterPluralRule - add or override the plural rule for a locale tag, so
+// languages outside the built-in CLDR table can be supported without forking.
+func (t *Template) RegisterPluralRule(tag string, fn PluralResolver) {
+	if t.pluralRules == nil {
+		t.pluralRules = map[string]PluralResolver{}
+	}
+	t.pluralRules[tag] = fn
+
+	if t.locale == tag {
+		t.pluralRule = fn
+	}
+}
+
+// RegisterGenderRule - override the default gender resolver
+func (t *Template) RegisterGenderRule(fn GenderResolver) {
+	t.genderRule = fn
+}
+
+// pluralCategory - resolve n to a CLDR plural category for the active locale
+func (t *Template) pluralCategory(n string) string {
+	if t.pluralRule == nil {
+		return pluralRuleEN(n)
+	}
+	return t.pluralRule(n)
+}
+
+// genderCategory - resolve value to a gender category
+func (t *Template) genderCategory(value string) string {
+	if t.genderRule == nil {
+		return defaultGenderRule(value)
+	}
+	return t.genderRule(value)
+}
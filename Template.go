@@ -0,0 +1,16 @@
+package docxplate
+
+// Template - the docx template being rendered. This file only declares the
+// fields/state introduced by the locale, filter and introspection features;
+// the document-loading and substitution machinery lives alongside the rest
+// of the package.
+type Template struct {
+	doc *xmlNode // parsed document tree, walked by ExtractParams
+
+	locale      string
+	pluralRules map[string]PluralResolver
+	pluralRule  PluralResolver
+	genderRule  GenderResolver
+
+	filters map[string]FilterFunc
+}
@@ -0,0 +1,540 @@
+package docxplate
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TriggerExpr - parsed ":if="/":unless=" condition attached to a ParamTrigger,
+// e.g. `Value>100`, `Status=="paid"`, or a bare `Items` reference. Evaluated
+// with the triggering Param's own Value as the implicit LHS, and any other
+// referenced absolute/compact key resolved through that Param's owning tree.
+type TriggerExpr struct {
+	root exprNode
+}
+
+// Eval - evaluate the expression against p
+func (e *TriggerExpr) Eval(p *Param) (bool, error) {
+	v, err := e.root.eval(p)
+	if err != nil {
+		return false, err
+	}
+	return v.truthy(), nil
+}
+
+// ParseTriggerExpr - parse the clause following "if="/"unless=" into a
+// TriggerExpr, via a hand-written recursive-descent parser (no reflection).
+func ParseTriggerExpr(raw string) (*TriggerExpr, error) {
+	toks, err := lexTriggerExpr(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	pr := &exprParser{tokens: toks}
+	node, err := pr.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !pr.atEnd() {
+		return nil, fmt.Errorf("docxplate: unexpected token %q in trigger expression %q", pr.peek().text, raw)
+	}
+
+	return &TriggerExpr{root: node}, nil
+}
+
+// --- values -----------------------------------------------------------
+
+type exprValueKind int
+
+const (
+	exprString exprValueKind = iota
+	exprNumber
+	exprBool
+)
+
+type exprValue struct {
+	kind exprValueKind
+	str  string
+	num  float64
+	b    bool
+}
+
+func stringValue(s string) exprValue { return exprValue{kind: exprString, str: s} }
+func boolValue(b bool) exprValue     { return exprValue{kind: exprBool, b: b} }
+
+// numberValue - string coerced to a number where possible, so "100" and
+// `"100"` compare equally against a numeric literal
+func numberValue(s string) (exprValue, bool) {
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return exprValue{}, false
+	}
+	return exprValue{kind: exprNumber, num: n, str: s}, true
+}
+
+func (v exprValue) truthy() bool {
+	switch v.kind {
+	case exprBool:
+		return v.b
+	case exprNumber:
+		return v.num != 0
+	default:
+		return v.str != "" && v.str != "0" && v.str != "false"
+	}
+}
+
+func (v exprValue) text() string {
+	if v.kind == exprBool {
+		return strconv.FormatBool(v.b)
+	}
+	return v.str
+}
+
+// --- AST ----------------------------------------------------------------
+
+type exprNode interface {
+	eval(p *Param) (exprValue, error)
+}
+
+// literalNode - string/number literal
+type literalNode struct {
+	value exprValue
+}
+
+func (n *literalNode) eval(*Param) (exprValue, error) { return n.value, nil }
+
+// refNode - bare identifier: another absolute/compact key, or "true"/"false"
+type refNode struct {
+	key string
+}
+
+func (n *refNode) eval(p *Param) (exprValue, error) {
+	switch n.key {
+	case "true":
+		return boolValue(true), nil
+	case "false":
+		return boolValue(false), nil
+	}
+
+	ref, ok := p.resolveRef(n.key)
+	if !ok {
+		return stringValue(""), nil // unknown key reads as empty/false, same as a missing template value
+	}
+
+	// A slice/list-parent Param (e.g. "Items" backing a {{#Items}} block)
+	// never gets a scalar Value - Param.Walk only ever populates its
+	// children. Judge it truthy by whether it has any entries instead.
+	if len(ref.Params) > 0 {
+		return boolValue(true), nil
+	}
+
+	return stringValue(ref.Value), nil
+}
+
+// implicitNode - the triggering Param's own Value, used as the LHS when a
+// comparison has no explicit reference, e.g. `:if=>100`
+type implicitNode struct{}
+
+func (n *implicitNode) eval(p *Param) (exprValue, error) { return stringValue(p.Value), nil }
+
+// unaryNode - "!" negation
+type unaryNode struct {
+	operand exprNode
+}
+
+func (n *unaryNode) eval(p *Param) (exprValue, error) {
+	v, err := n.operand.eval(p)
+	if err != nil {
+		return exprValue{}, err
+	}
+	return boolValue(!v.truthy()), nil
+}
+
+// logicalNode - "&&" / "||"
+type logicalNode struct {
+	op          string
+	left, right exprNode
+}
+
+func (n *logicalNode) eval(p *Param) (exprValue, error) {
+	l, err := n.left.eval(p)
+	if err != nil {
+		return exprValue{}, err
+	}
+
+	if n.op == "&&" && !l.truthy() {
+		return boolValue(false), nil
+	}
+	if n.op == "||" && l.truthy() {
+		return boolValue(true), nil
+	}
+
+	r, err := n.right.eval(p)
+	if err != nil {
+		return exprValue{}, err
+	}
+	return boolValue(r.truthy()), nil
+}
+
+// compareNode - "==", "!=", "<", "<=", ">", ">=", "=~"
+type compareNode struct {
+	op          string
+	left, right exprNode
+}
+
+func (n *compareNode) eval(p *Param) (exprValue, error) {
+	l, err := n.left.eval(p)
+	if err != nil {
+		return exprValue{}, err
+	}
+	r, err := n.right.eval(p)
+	if err != nil {
+		return exprValue{}, err
+	}
+
+	if n.op == "=~" {
+		re, err := regexp.Compile(r.text())
+		if err != nil {
+			return exprValue{}, fmt.Errorf("docxplate: invalid =~ pattern %q: %w", r.text(), err)
+		}
+		return boolValue(re.MatchString(l.text())), nil
+	}
+
+	ln, lok := numberValue(l.text())
+	rn, rok := numberValue(r.text())
+	if lok && rok {
+		return boolValue(compareNumbers(n.op, ln.num, rn.num)), nil
+	}
+
+	return boolValue(compareStrings(n.op, l.text(), r.text())), nil
+}
+
+func compareNumbers(op string, l, r float64) bool {
+	switch op {
+	case "==":
+		return l == r
+	case "!=":
+		return l != r
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	case ">":
+		return l > r
+	case ">=":
+		return l >= r
+	}
+	return false
+}
+
+func compareStrings(op string, l, r string) bool {
+	switch op {
+	case "==":
+		return l == r
+	case "!=":
+		return l != r
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	case ">":
+		return l > r
+	case ">=":
+		return l >= r
+	}
+	return false
+}
+
+// --- lexer ----------------------------------------------------------------
+
+type exprTokenKind int
+
+const (
+	tokEOF exprTokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type exprToken struct {
+	kind exprTokenKind
+	text string
+}
+
+var exprOps = []string{"==", "!=", "<=", ">=", "=~", "&&", "||", "<", ">", "!"}
+
+func lexTriggerExpr(raw string) ([]exprToken, error) {
+	var tokens []exprToken
+	s := raw
+
+	for len(s) > 0 {
+		switch {
+		case s[0] == ' ':
+			s = s[1:]
+
+		case s[0] == '(':
+			tokens = append(tokens, exprToken{kind: tokLParen, text: "("})
+			s = s[1:]
+
+		case s[0] == ')':
+			tokens = append(tokens, exprToken{kind: tokRParen, text: ")"})
+			s = s[1:]
+
+		case s[0] == '"':
+			end := strings.IndexByte(s[1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("docxplate: unterminated string in trigger expression %q", raw)
+			}
+			tokens = append(tokens, exprToken{kind: tokString, text: s[1 : end+1]})
+			s = s[end+2:]
+
+		case isExprOpStart(s):
+			op := matchExprOp(s)
+			tokens = append(tokens, exprToken{kind: tokOp, text: op})
+			s = s[len(op):]
+
+		// Unary minus on a numeric literal, e.g. "Balance<-50". There's no
+		// subtraction operator in this grammar, so a leading '-' right
+		// before a digit always belongs to the number that follows.
+		case s[0] == '-' && len(s) > 1 && isDigit(s[1]):
+			i := 1
+			for i < len(s) && (isDigit(s[i]) || s[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: tokNumber, text: s[:i]})
+			s = s[i:]
+
+		case isDigit(s[0]):
+			i := 0
+			for i < len(s) && (isDigit(s[i]) || s[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: tokNumber, text: s[:i]})
+			s = s[i:]
+
+		case isIdentStart(s[0]):
+			i := 0
+			for i < len(s) && isIdentPart(s[i]) {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: tokIdent, text: s[:i]})
+			s = s[i:]
+
+		default:
+			return nil, fmt.Errorf("docxplate: unexpected character %q in trigger expression %q", s[0], raw)
+		}
+	}
+
+	return tokens, nil
+}
+
+func isExprOpStart(s string) bool {
+	return matchExprOp(s) != ""
+}
+
+func matchExprOp(s string) string {
+	for _, op := range exprOps {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c) || c == '.'
+}
+
+// --- parser -----------------------------------------------------------
+
+// exprParser - recursive-descent parser over a flat token stream;
+// precedence (low to high): || , && , ! , comparison , primary
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+}
+
+func (pr *exprParser) peek() exprToken {
+	if pr.atEnd() {
+		return exprToken{kind: tokEOF}
+	}
+	return pr.tokens[pr.pos]
+}
+
+func (pr *exprParser) atEnd() bool { return pr.pos >= len(pr.tokens) }
+
+func (pr *exprParser) next() exprToken {
+	t := pr.peek()
+	pr.pos++
+	return t
+}
+
+func (pr *exprParser) parseOr() (exprNode, error) {
+	left, err := pr.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for pr.peek().kind == tokOp && pr.peek().text == "||" {
+		pr.next()
+		right, err := pr.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "||", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (pr *exprParser) parseAnd() (exprNode, error) {
+	left, err := pr.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for pr.peek().kind == tokOp && pr.peek().text == "&&" {
+		pr.next()
+		right, err := pr.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "&&", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (pr *exprParser) parseUnary() (exprNode, error) {
+	if pr.peek().kind == tokOp && pr.peek().text == "!" {
+		pr.next()
+		operand, err := pr.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{operand: operand}, nil
+	}
+
+	return pr.parseComparison()
+}
+
+var compareOps = map[string]bool{"==": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true, "=~": true}
+
+func (pr *exprParser) parseComparison() (exprNode, error) {
+	left, err := pr.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	if pr.peek().kind == tokOp && compareOps[pr.peek().text] {
+		op := pr.next().text
+		right, err := pr.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return &compareNode{op: op, left: left, right: right}, nil
+	}
+
+	return left, nil
+}
+
+func (pr *exprParser) parsePrimary() (exprNode, error) {
+	tok := pr.peek()
+
+	switch tok.kind {
+	case tokLParen:
+		pr.next()
+		node, err := pr.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if pr.peek().kind != tokRParen {
+			return nil, fmt.Errorf("docxplate: missing closing ')' in trigger expression")
+		}
+		pr.next()
+		return node, nil
+
+	case tokString:
+		pr.next()
+		return &literalNode{value: stringValue(tok.text)}, nil
+
+	case tokNumber:
+		pr.next()
+		v, _ := numberValue(tok.text)
+		return &literalNode{value: v}, nil
+
+	case tokIdent:
+		pr.next()
+		return &refNode{key: tok.text}, nil
+
+	// A comparison operator with no LHS ("if=>100") implicitly compares the
+	// triggering Param's own Value.
+	case tokOp:
+		if compareOps[tok.text] {
+			return &implicitNode{}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("docxplate: unexpected token %q in trigger expression", tok.text)
+}
+
+// --- extraction from raw placeholder trigger text ------------------------
+
+// ifUnlessPrefixes - clause markers recognized ahead of a trigger expression
+var ifUnlessPrefixes = []string{":if=", ":unless="}
+
+// extractIfUnless - pull an "if="/"unless=" clause out of raw trigger
+// contents (e.g. ":if=Value>100:remove:row"), returning the parsed
+// TriggerExpr, whether it was negated (":unless="), and the raw bytes left
+// over for NewParamTrigger to parse as usual. Returns a nil expr and the
+// untouched raw when there's no such clause.
+func extractIfUnless(raw []byte) (expr *TriggerExpr, negate bool, rest []byte) {
+	s := string(raw)
+
+	for _, prefix := range ifUnlessPrefixes {
+		idx := strings.Index(s, prefix)
+		if idx < 0 {
+			continue
+		}
+
+		body := s[idx+len(prefix):]
+		end := exprCommandBoundary(body)
+
+		e, err := ParseTriggerExpr(body[:end])
+		if err != nil {
+			return nil, false, raw
+		}
+
+		return e, prefix == ":unless=", []byte(s[:idx] + body[end:])
+	}
+
+	return nil, false, raw
+}
+
+// exprCommandBoundary - index in body where the next ":remove"/":clear"
+// trigger command starts (outside quoted string args), or len(body) if none
+func exprCommandBoundary(body string) int {
+	inQuotes := false
+	for i, c := range body {
+		switch {
+		case c == '"':
+			inQuotes = !inQuotes
+		case c == ':' && !inQuotes:
+			if strings.HasPrefix(body[i:], ":remove") || strings.HasPrefix(body[i:], ":clear") {
+				return i
+			}
+		}
+	}
+	return len(body)
+}
@@ -0,0 +1,101 @@
+package docxplate
+
+import "testing"
+
+func TestLexTriggerExpr_NegativeNumber(t *testing.T) {
+	toks, err := lexTriggerExpr("Balance<-50")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []exprToken{
+		{kind: tokIdent, text: "Balance"},
+		{kind: tokOp, text: "<"},
+		{kind: tokNumber, text: "-50"},
+	}
+	if len(toks) != len(want) {
+		t.Fatalf("got %d tokens, want %d: %+v", len(toks), len(want), toks)
+	}
+	for i, tok := range toks {
+		if tok.kind != want[i].kind || tok.text != want[i].text {
+			t.Fatalf("token %d = %+v, want %+v", i, tok, want[i])
+		}
+	}
+}
+
+func TestParseTriggerExpr_NegativeNumber(t *testing.T) {
+	expr, err := ParseTriggerExpr("Balance<-50")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	p := NewParam("Balance")
+	p.AbsoluteKey, p.CompactKey = "Balance", "Balance"
+	p.Value = "-100"
+
+	ok, err := expr.Eval(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("expected -100 < -50 to be true")
+	}
+}
+
+func TestParseTriggerExpr_SliceRefTruthy(t *testing.T) {
+	expr, err := ParseTriggerExpr("Items")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	items := NewParam("Items")
+	items.AbsoluteKey, items.CompactKey = "Items", "Items"
+	items.IsSlice = true
+	items.Params = ParamList{NewParam("Row1"), NewParam("Row2")}
+
+	ok, err := expr.Eval(items)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("expected a slice Param with entries to be truthy")
+	}
+}
+
+func TestParseTriggerExpr_SliceRefEmptyIsFalsy(t *testing.T) {
+	expr, err := ParseTriggerExpr("Items")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	items := NewParam("Items")
+	items.AbsoluteKey, items.CompactKey = "Items", "Items"
+	items.IsSlice = true
+
+	ok, err := expr.Eval(items)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Fatalf("expected a slice Param with no entries to be falsy")
+	}
+}
+
+func TestParseTriggerExpr_Comparison(t *testing.T) {
+	expr, err := ParseTriggerExpr(`Status=="paid"`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	p := NewParam("Status")
+	p.AbsoluteKey, p.CompactKey = "Status", "Status"
+	p.Value = "paid"
+
+	ok, err := expr.Eval(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("expected Status==\"paid\" to be true")
+	}
+}